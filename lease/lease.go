@@ -0,0 +1,152 @@
+// Package lease adds a TTL and automatic keepalive on top of any
+// yalock.Locker, for drivers (or use cases) that expect a fencing lease
+// rather than acquire-and-forget semantics.
+package lease
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bensooraj/yalock"
+)
+
+// Renewer is implemented by drivers that can extend an already-acquired
+// lock's TTL directly (e.g. redis.RedisLock.Refresh). Drivers that don't
+// implement it are kept alive with a plain IsLockAcquired heartbeat
+// instead, which is sufficient for session-scoped locks such as MySQL's
+// GET_LOCK or Postgres's pg_advisory_lock: as long as the underlying
+// session is alive, the lock stays held.
+type Renewer interface {
+	Refresh(ctx context.Context) error
+}
+
+// LostOwnershipError is delivered through LeasedLock.Err when the
+// keepalive goroutine fails to renew or heartbeat the lease before it
+// would expire.
+type LostOwnershipError struct {
+	Key string
+	Err error
+}
+
+func (e *LostOwnershipError) Error() string {
+	return fmt.Sprintf("lease: lost ownership of lock %q: %s", e.Key, e.Err)
+}
+
+func (e *LostOwnershipError) Unwrap() error {
+	return e.Err
+}
+
+// LeasedLock wraps a yalock.Locker with a TTL and a background goroutine
+// that re-asserts ownership at refreshInterval, following the pattern used
+// by long-running daemons that must relinquish work as soon as they lose
+// their lock.
+type LeasedLock struct {
+	locker yalock.Locker
+	key    string
+	ttl    time.Duration
+
+	cancel  context.CancelFunc
+	done    chan struct{}
+	stopped chan struct{} // closed by keepalive right before it returns
+	once    sync.Once
+
+	mu  sync.Mutex
+	err error
+}
+
+// NewLeasedLock acquires key on locker with the given ttl, then starts a
+// goroutine that renews the lease every refreshInterval until Release is
+// called or the lease is lost. refreshInterval should be comfortably
+// shorter than ttl.
+func NewLeasedLock(ctx context.Context, locker yalock.Locker, key string, ttl, refreshInterval time.Duration) (*LeasedLock, error) {
+	if err := locker.AcquireLock(ctx, key, ttl); err != nil {
+		return nil, err
+	}
+
+	leaseCtx, cancel := context.WithCancel(context.Background())
+	l := &LeasedLock{
+		locker:  locker,
+		key:     key,
+		ttl:     ttl,
+		cancel:  cancel,
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go l.keepalive(leaseCtx, refreshInterval)
+	return l, nil
+}
+
+// Done returns a channel that is closed once the lease is lost or
+// released. Callers should select on it and abandon their work if it
+// closes with Err() returning a non-nil *LostOwnershipError.
+func (l *LeasedLock) Done() <-chan struct{} {
+	return l.done
+}
+
+// Err returns the reason the lease ended, or nil if it was released
+// cleanly via Release.
+func (l *LeasedLock) Err() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.err
+}
+
+// Release stops the keepalive goroutine and releases the underlying lock.
+// It waits for the goroutine to actually exit first, so a renew in flight
+// can never race ReleaseLock on the same underlying session, and so
+// Done is guaranteed closed with Err() left nil before ReleaseLock runs,
+// same as a lease that was never lost.
+func (l *LeasedLock) Release(ctx context.Context) error {
+	l.cancel()
+	<-l.stopped
+	l.once.Do(func() { close(l.done) })
+	return l.locker.ReleaseLock(ctx, l.key)
+}
+
+func (l *LeasedLock) keepalive(ctx context.Context, refreshInterval time.Duration) {
+	defer close(l.stopped)
+
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := l.renew(ctx); err != nil {
+				if ctx.Err() != nil {
+					// Release canceled us mid-renew: ordinary shutdown,
+					// not a lost lease.
+					return
+				}
+				l.fail(err)
+				return
+			}
+		}
+	}
+}
+
+func (l *LeasedLock) renew(ctx context.Context) error {
+	if r, ok := l.locker.(Renewer); ok {
+		return r.Refresh(ctx)
+	}
+
+	held, err := l.locker.IsLockAcquired(ctx, l.key)
+	if err != nil {
+		return err
+	}
+	if !held {
+		return fmt.Errorf("lock %q is no longer held", l.key)
+	}
+	return nil
+}
+
+func (l *LeasedLock) fail(err error) {
+	l.mu.Lock()
+	l.err = &LostOwnershipError{Key: l.key, Err: err}
+	l.mu.Unlock()
+	l.once.Do(func() { close(l.done) })
+}
@@ -0,0 +1,107 @@
+package lease_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bensooraj/yalock/lease"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLocker is a minimal yalock.Locker that doesn't implement
+// lease.Renewer, so LeasedLock falls back to its IsLockAcquired heartbeat.
+type fakeLocker struct {
+	mu       sync.Mutex
+	acquired bool
+	lost     bool
+}
+
+func (f *fakeLocker) Name() string { return "fake" }
+
+func (f *fakeLocker) AcquireLock(ctx context.Context, key string, timeout time.Duration) error {
+	f.mu.Lock()
+	f.acquired = true
+	f.mu.Unlock()
+	return nil
+}
+
+func (f *fakeLocker) ReleaseLock(ctx context.Context, key string) error {
+	f.mu.Lock()
+	f.acquired = false
+	f.mu.Unlock()
+	return nil
+}
+
+func (f *fakeLocker) IsLockAcquired(ctx context.Context, key string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.lost {
+		return false, nil
+	}
+	return f.acquired, nil
+}
+
+func (f *fakeLocker) IsLockFree(ctx context.Context, key string) (bool, error) {
+	acquired, err := f.IsLockAcquired(ctx, key)
+	return !acquired, err
+}
+
+func (f *fakeLocker) ReleaseAllLocks(ctx context.Context) (int, error) {
+	return 0, nil
+}
+
+func (f *fakeLocker) loseLock() {
+	f.mu.Lock()
+	f.lost = true
+	f.mu.Unlock()
+}
+
+func TestLeasedLock_ReleaseClosesDoneCleanly(t *testing.T) {
+	f := &fakeLocker{}
+	l, err := lease.NewLeasedLock(context.Background(), f, "key", time.Second, 10*time.Millisecond)
+	require.NoError(t, err)
+
+	require.NoError(t, l.Release(context.Background()))
+
+	select {
+	case <-l.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Done() did not close after Release")
+	}
+	assert.NoError(t, l.Err())
+}
+
+func TestLeasedLock_LostOwnershipSetsErr(t *testing.T) {
+	f := &fakeLocker{}
+	l, err := lease.NewLeasedLock(context.Background(), f, "key", time.Second, 5*time.Millisecond)
+	require.NoError(t, err)
+	defer l.Release(context.Background())
+
+	f.loseLock()
+
+	select {
+	case <-l.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Done() did not close after the lock was lost")
+	}
+
+	var lostErr *lease.LostOwnershipError
+	assert.ErrorAs(t, l.Err(), &lostErr)
+}
+
+// TestLeasedLock_ReleaseRaceNoSpuriousLostOwnership guards against a clean
+// Release racing a keepalive tick: Release must never leave Err() set to a
+// LostOwnershipError caused by its own cancellation. Run with -race.
+func TestLeasedLock_ReleaseRaceNoSpuriousLostOwnership(t *testing.T) {
+	for i := 0; i < 200; i++ {
+		f := &fakeLocker{}
+		l, err := lease.NewLeasedLock(context.Background(), f, "key", time.Second, time.Millisecond)
+		require.NoError(t, err)
+
+		require.NoError(t, l.Release(context.Background()))
+		assert.NoError(t, l.Err())
+	}
+}
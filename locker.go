@@ -0,0 +1,36 @@
+package yalock
+
+import (
+	"context"
+	"time"
+)
+
+// Locker is the common surface implemented by every yalock driver (mysql,
+// postgres, redis, ...). It lets callers pick a backend and swap it later
+// without rewriting call sites.
+type Locker interface {
+	// Name returns the name given to this locker instance when it was
+	// constructed.
+	Name() string
+
+	// AcquireLock attempts to acquire the named lock identified by key,
+	// waiting up to timeout for it to become available. A negative
+	// timeout means wait indefinitely.
+	AcquireLock(ctx context.Context, key string, timeout time.Duration) error
+
+	// ReleaseLock releases the lock identified by key, if it is held by
+	// this locker.
+	ReleaseLock(ctx context.Context, key string) error
+
+	// IsLockAcquired reports whether the lock identified by key is
+	// currently held, by any locker.
+	IsLockAcquired(ctx context.Context, key string) (bool, error)
+
+	// IsLockFree reports whether the lock identified by key is currently
+	// free.
+	IsLockFree(ctx context.Context, key string) (bool, error)
+
+	// ReleaseAllLocks releases every lock held by this locker and
+	// returns how many were released.
+	ReleaseAllLocks(ctx context.Context) (int, error)
+}
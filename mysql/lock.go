@@ -3,24 +3,129 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"sync"
 	"time"
+
+	"github.com/bensooraj/yalock"
+	"github.com/bensooraj/yalock/observability"
 )
 
-func NewMySQLLock(name string, db *sql.DB) *MySQLLock {
-	return &MySQLLock{name: name, db: db}
+var _ yalock.Locker = (*MySQLLock)(nil)
+
+// Option configures optional behavior on a MySQLLock.
+type Option func(*MySQLLock)
+
+// WithLogger makes the lock emit structured events through logger instead
+// of discarding them.
+func WithLogger(logger observability.Logger) Option {
+	return func(l *MySQLLock) { l.logger = logger }
+}
+
+// WithMetrics makes the lock emit acquire/release metrics through metrics
+// instead of discarding them.
+func WithMetrics(metrics observability.Metrics) Option {
+	return func(l *MySQLLock) { l.metrics = metrics }
+}
+
+func NewMySQLLock(name string, db *sql.DB, opts ...Option) *MySQLLock {
+	l := &MySQLLock{
+		name:      name,
+		db:        db,
+		logger:    observability.NoopLogger{},
+		metrics:   observability.NoopMetrics{},
+		heldSince: make(map[string]time.Time),
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
 }
 
 // Documentation: https://dev.mysql.com/doc/refman/5.7/en/locking-functions.html#function_release-lock
 type MySQLLock struct {
 	name string
 	db   *sql.DB
+
+	logger  observability.Logger
+	metrics observability.Metrics
+
+	mu        sync.Mutex
+	heldSince map[string]time.Time
 }
 
 func (l *MySQLLock) Name() string {
 	return l.name
 }
 
+// AcquireLock is a thin wrapper around AcquireLockWithOptions that makes a
+// single attempt, matching the original GET_LOCK-once behavior.
 func (l *MySQLLock) AcquireLock(ctx context.Context, key string, timeout time.Duration) error {
+	return l.AcquireLockWithOptions(ctx, key, yalock.AcquireOptions{
+		Timeout:     timeout,
+		RetryPolicy: yalock.RetryPolicy{MaxAttempts: 1},
+	})
+}
+
+// AcquireLockWithOptions makes up to opts.RetryPolicy.MaxAttempts attempts
+// in total (or keeps going until ctx is done, if MaxAttempts is zero or
+// negative), each attempt calling GET_LOCK with opts.Timeout. Only a
+// GET_LOCK timeout is retried; any other error is returned at once. This lets callers subdivide a long overall
+// deadline into shorter GET_LOCK timeouts so ctx cancellation is observed
+// promptly, instead of blocking inside a single GET_LOCK call.
+func (l *MySQLLock) AcquireLockWithOptions(ctx context.Context, key string, opts yalock.AcquireOptions) (err error) {
+	ctx, endSpan := observability.StartSpan(ctx, "Acquire", "mysql", l.name, key)
+	start := time.Now()
+	defer func() {
+		l.metrics.ObserveAcquireDuration("mysql", l.name, time.Since(start))
+		endSpan(err)
+	}()
+
+	unbounded := opts.RetryPolicy.MaxAttempts <= 0
+
+	for attempt := 0; unbounded || attempt < opts.RetryPolicy.MaxAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				err = ctx.Err()
+				return err
+			case <-time.After(opts.RetryPolicy.Delay(attempt - 1)):
+			}
+		}
+
+		l.metrics.IncAcquireAttempts("mysql", l.name)
+		err = l.acquireOnce(ctx, key, opts.Timeout)
+		if err == nil {
+			l.logger.Info("lock acquired", "driver", "mysql", "name", l.name, "key", key)
+			l.mu.Lock()
+			l.heldSince[key] = time.Now()
+			l.metrics.SetLocksHeld("mysql", l.name, len(l.heldSince))
+			l.mu.Unlock()
+			return nil
+		}
+
+		l.metrics.IncAcquireFailures("mysql", l.name, failureReason(err))
+		if !errors.Is(err, ErrorLockTimeout) {
+			l.logger.Error("lock acquisition failed", "driver", "mysql", "name", l.name, "key", key, "error", err)
+			return err
+		}
+		l.logger.Warn("lock acquisition timed out, retrying", "driver", "mysql", "name", l.name, "key", key)
+	}
+	return err
+}
+
+func failureReason(err error) observability.AcquireFailureReason {
+	switch {
+	case errors.Is(err, ErrorLockTimeout):
+		return observability.ReasonTimeout
+	case errors.Is(err, ErrorLockAcquisitionFailed):
+		return observability.ReasonDenied
+	default:
+		return observability.ReasonError
+	}
+}
+
+func (l *MySQLLock) acquireOnce(ctx context.Context, key string, timeout time.Duration) error {
 	var result sql.NullInt16
 
 	row := l.db.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", key, int(timeout.Seconds()))
@@ -80,42 +185,60 @@ func (l *MySQLLock) AcquireLock(ctx context.Context, key string, timeout time.Du
 	return nil
 }
 
-func (l *MySQLLock) ReleaseLock(ctx context.Context, key string) error {
+func (l *MySQLLock) ReleaseLock(ctx context.Context, key string) (err error) {
+	ctx, endSpan := observability.StartSpan(ctx, "Release", "mysql", l.name, key)
+	defer func() { endSpan(err) }()
+
 	var result sql.NullInt16
 	row := l.db.QueryRowContext(ctx, "SELECT RELEASE_LOCK(?)", key)
 	if row.Err() != nil {
 		return row.Err()
 	}
-	err := row.Scan(&result)
-	if err != nil {
+	if err = row.Scan(&result); err != nil {
 		return err
 	}
 	switch {
 	case !result.Valid: // NULL
 		// the named lock did not exist
-		return &LockError{
+		err = &LockError{
 			Err:         ErrorLockDoesNotExist,
 			Message:     "lock does not exist",
 			Method:      "ReleaseLock",
 			SessionName: l.name,
 			Driver:      "mysql",
 		}
+		l.logger.Error("release failed: lock does not exist", "driver", "mysql", "name", l.name, "key", key)
+		return err
 	case result.Int16 == 0:
 		// lock was not established by this thread (in which case the lock is not released)
-		return &LockError{
+		err = &LockError{
 			Err:         ErrorLockNotOwned,
 			Message:     "lock not owned",
 			Method:      "ReleaseLock",
 			SessionName: l.name,
 			Driver:      "mysql",
 		}
+		l.logger.Error("release failed: lock not owned", "driver", "mysql", "name", l.name, "key", key)
+		return err
 	case result.Int16 == 1:
-		// log.Printf("[ReleaseLock::`%s`] lock on `%s` released", l.name, key)
+		l.logger.Info("lock released", "driver", "mysql", "name", l.name, "key", key)
 	}
+
+	l.mu.Lock()
+	if since, ok := l.heldSince[key]; ok {
+		l.metrics.ObserveHeldDuration("mysql", l.name, time.Since(since))
+		delete(l.heldSince, key)
+	}
+	l.metrics.SetLocksHeld("mysql", l.name, len(l.heldSince))
+	l.mu.Unlock()
+	l.metrics.IncRelease("mysql", l.name)
+
 	return nil
 }
 
 func (l *MySQLLock) IsLockAcquired(ctx context.Context, key string) (bool, error) {
+	l.logger.Debug("checking IS_USED_LOCK", "driver", "mysql", "name", l.name, "key", key)
+
 	var result sql.NullString
 	row := l.db.QueryRowContext(ctx, "SELECT IS_USED_LOCK(?)", key)
 	if row.Err() != nil {
@@ -134,6 +257,8 @@ func (l *MySQLLock) IsLockAcquired(ctx context.Context, key string) (bool, error
 }
 
 func (l *MySQLLock) IsLockFree(ctx context.Context, key string) (bool, error) {
+	l.logger.Debug("checking IS_FREE_LOCK", "driver", "mysql", "name", l.name, "key", key)
+
 	var result sql.NullInt16
 	row := l.db.QueryRowContext(ctx, "SELECT IS_FREE_LOCK(?)", key)
 	if row.Err() != nil {
@@ -165,6 +290,8 @@ func (l *MySQLLock) IsLockFree(ctx context.Context, key string) (bool, error) {
 }
 
 func (l *MySQLLock) ReleaseAllLocks(ctx context.Context) (int, error) {
+	l.logger.Debug("releasing all locks", "driver", "mysql", "name", l.name)
+
 	var result sql.NullInt32
 	row := l.db.QueryRowContext(ctx, "SELECT RELEASE_ALL_LOCKS()")
 	if row.Err() != nil {
@@ -174,5 +301,11 @@ func (l *MySQLLock) ReleaseAllLocks(ctx context.Context) (int, error) {
 	if err != nil {
 		return 0, err
 	}
+
+	l.mu.Lock()
+	l.heldSince = make(map[string]time.Time)
+	l.metrics.SetLocksHeld("mysql", l.name, 0)
+	l.mu.Unlock()
+
 	return int(result.Int32), nil
 }
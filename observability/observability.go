@@ -0,0 +1,63 @@
+// Package observability provides the pluggable logging, metrics and
+// tracing surface that the mysql driver and postgres.PostgreSQLLock emit
+// around every Acquire/Release. redis.RedisLock and postgres.TableLock do
+// not emit through it.
+package observability
+
+import "time"
+
+// Logger is the structured logging surface yalock drivers emit
+// debug/info/warn/error events through. kv are alternating key/value
+// pairs, mirroring log/slog's convention.
+type Logger interface {
+	Debug(msg string, kv ...any)
+	Info(msg string, kv ...any)
+	Warn(msg string, kv ...any)
+	Error(msg string, kv ...any)
+}
+
+// NoopLogger discards every call. It is the default for drivers that
+// aren't constructed with a logger option.
+type NoopLogger struct{}
+
+func (NoopLogger) Debug(msg string, kv ...any) {}
+func (NoopLogger) Info(msg string, kv ...any)  {}
+func (NoopLogger) Warn(msg string, kv ...any)  {}
+func (NoopLogger) Error(msg string, kv ...any) {}
+
+// AcquireFailureReason categorizes why AcquireLock failed, for the
+// acquire_failures_total{reason=...} metric.
+type AcquireFailureReason string
+
+const (
+	ReasonTimeout AcquireFailureReason = "timeout"
+	ReasonDenied  AcquireFailureReason = "denied"
+	ReasonError   AcquireFailureReason = "error"
+)
+
+// Metrics is the counters/histograms the mysql and postgres advisory-lock
+// drivers emit around every Acquire/Release. Implementations must be safe for concurrent use.
+type Metrics interface {
+	IncAcquireAttempts(driver, name string)
+	IncAcquireFailures(driver, name string, reason AcquireFailureReason)
+	ObserveAcquireDuration(driver, name string, d time.Duration)
+	ObserveHeldDuration(driver, name string, d time.Duration)
+	IncRelease(driver, name string)
+	SetLocksHeld(driver, name string, n int)
+}
+
+// NoopMetrics discards every call. It is the default for drivers that
+// aren't constructed with a metrics option.
+type NoopMetrics struct{}
+
+func (NoopMetrics) IncAcquireAttempts(driver, name string)                             {}
+func (NoopMetrics) IncAcquireFailures(driver, name string, reason AcquireFailureReason) {}
+func (NoopMetrics) ObserveAcquireDuration(driver, name string, d time.Duration)         {}
+func (NoopMetrics) ObserveHeldDuration(driver, name string, d time.Duration)            {}
+func (NoopMetrics) IncRelease(driver, name string)                                      {}
+func (NoopMetrics) SetLocksHeld(driver, name string, n int)                             {}
+
+var (
+	_ Logger  = NoopLogger{}
+	_ Metrics = NoopMetrics{}
+)
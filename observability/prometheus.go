@@ -0,0 +1,93 @@
+package observability
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PrometheusMetrics adapts a prometheus.Registerer to Metrics, registering
+// the acquire_attempts_total, acquire_failures_total, acquire_duration_seconds,
+// held_duration_seconds, release_total and locks_held collectors under the
+// "yalock" namespace.
+type PrometheusMetrics struct {
+	acquireAttempts *prometheus.CounterVec
+	acquireFailures *prometheus.CounterVec
+	acquireDuration *prometheus.HistogramVec
+	heldDuration    *prometheus.HistogramVec
+	releases        *prometheus.CounterVec
+	locksHeld       *prometheus.GaugeVec
+}
+
+// NewPrometheusMetrics creates the yalock collectors and registers them
+// against reg.
+func NewPrometheusMetrics(reg prometheus.Registerer) (*PrometheusMetrics, error) {
+	m := &PrometheusMetrics{
+		acquireAttempts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "yalock",
+			Name:      "acquire_attempts_total",
+			Help:      "Total number of lock acquisition attempts.",
+		}, []string{"driver", "name"}),
+		acquireFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "yalock",
+			Name:      "acquire_failures_total",
+			Help:      "Total number of failed lock acquisition attempts, by reason.",
+		}, []string{"driver", "name", "reason"}),
+		acquireDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "yalock",
+			Name:      "acquire_duration_seconds",
+			Help:      "Time spent attempting to acquire a lock.",
+		}, []string{"driver", "name"}),
+		heldDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "yalock",
+			Name:      "held_duration_seconds",
+			Help:      "Time a lock was held between Acquire and Release.",
+		}, []string{"driver", "name"}),
+		releases: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "yalock",
+			Name:      "release_total",
+			Help:      "Total number of lock releases.",
+		}, []string{"driver", "name"}),
+		locksHeld: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "yalock",
+			Name:      "locks_held",
+			Help:      "Current number of locks held.",
+		}, []string{"driver", "name"}),
+	}
+
+	for _, c := range []prometheus.Collector{
+		m.acquireAttempts, m.acquireFailures, m.acquireDuration,
+		m.heldDuration, m.releases, m.locksHeld,
+	} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+func (m *PrometheusMetrics) IncAcquireAttempts(driver, name string) {
+	m.acquireAttempts.WithLabelValues(driver, name).Inc()
+}
+
+func (m *PrometheusMetrics) IncAcquireFailures(driver, name string, reason AcquireFailureReason) {
+	m.acquireFailures.WithLabelValues(driver, name, string(reason)).Inc()
+}
+
+func (m *PrometheusMetrics) ObserveAcquireDuration(driver, name string, d time.Duration) {
+	m.acquireDuration.WithLabelValues(driver, name).Observe(d.Seconds())
+}
+
+func (m *PrometheusMetrics) ObserveHeldDuration(driver, name string, d time.Duration) {
+	m.heldDuration.WithLabelValues(driver, name).Observe(d.Seconds())
+}
+
+func (m *PrometheusMetrics) IncRelease(driver, name string) {
+	m.releases.WithLabelValues(driver, name).Inc()
+}
+
+func (m *PrometheusMetrics) SetLocksHeld(driver, name string, n int) {
+	m.locksHeld.WithLabelValues(driver, name).Set(float64(n))
+}
+
+var _ Metrics = (*PrometheusMetrics)(nil)
@@ -0,0 +1,58 @@
+package observability_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bensooraj/yalock/observability"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheusMetrics(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m, err := observability.NewPrometheusMetrics(reg)
+	require.NoError(t, err)
+
+	m.IncAcquireAttempts("mysql", "orders")
+	m.IncAcquireAttempts("mysql", "orders")
+	m.IncAcquireFailures("mysql", "orders", observability.ReasonTimeout)
+	m.ObserveAcquireDuration("mysql", "orders", 10*time.Millisecond)
+	m.ObserveHeldDuration("mysql", "orders", 20*time.Millisecond)
+	m.IncRelease("mysql", "orders")
+	m.SetLocksHeld("mysql", "orders", 3)
+
+	mfs, err := reg.Gather()
+	require.NoError(t, err)
+
+	assert.Equal(t, float64(2), counterValue(t, mfs, "yalock_acquire_attempts_total"))
+	assert.Equal(t, float64(1), counterValue(t, mfs, "yalock_acquire_failures_total"))
+	assert.Equal(t, float64(1), counterValue(t, mfs, "yalock_release_total"))
+	assert.Equal(t, float64(3), gaugeValue(t, mfs, "yalock_locks_held"))
+}
+
+func counterValue(t *testing.T, mfs []*dto.MetricFamily, name string) float64 {
+	t.Helper()
+	mf := findFamily(t, mfs, name)
+	return mf.Metric[0].GetCounter().GetValue()
+}
+
+func gaugeValue(t *testing.T, mfs []*dto.MetricFamily, name string) float64 {
+	t.Helper()
+	mf := findFamily(t, mfs, name)
+	return mf.Metric[0].GetGauge().GetValue()
+}
+
+func findFamily(t *testing.T, mfs []*dto.MetricFamily, name string) *dto.MetricFamily {
+	t.Helper()
+	for _, mf := range mfs {
+		if mf.GetName() == name {
+			require.Len(t, mf.Metric, 1, "expected exactly one label combination for %q", name)
+			return mf
+		}
+	}
+	t.Fatalf("metric %q not registered", name)
+	return nil
+}
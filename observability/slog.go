@@ -0,0 +1,19 @@
+package observability
+
+import "log/slog"
+
+// SlogLogger adapts a *slog.Logger to Logger.
+type SlogLogger struct {
+	L *slog.Logger
+}
+
+func NewSlogLogger(l *slog.Logger) SlogLogger {
+	return SlogLogger{L: l}
+}
+
+func (s SlogLogger) Debug(msg string, kv ...any) { s.L.Debug(msg, kv...) }
+func (s SlogLogger) Info(msg string, kv ...any)  { s.L.Info(msg, kv...) }
+func (s SlogLogger) Warn(msg string, kv ...any)  { s.L.Warn(msg, kv...) }
+func (s SlogLogger) Error(msg string, kv ...any) { s.L.Error(msg, kv...) }
+
+var _ Logger = SlogLogger{}
@@ -0,0 +1,31 @@
+package observability
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/bensooraj/yalock")
+
+// StartSpan starts a span named "yalock."+operation (operation is e.g.
+// "Acquire" or "Release") with the lock's driver, name and key as
+// attributes, so contention is traceable end to end. The returned func
+// ends the span, recording err on it if non-nil.
+func StartSpan(ctx context.Context, operation, driver, name, key string) (context.Context, func(error)) {
+	ctx, span := tracer.Start(ctx, "yalock."+operation, trace.WithAttributes(
+		attribute.String("yalock.driver", driver),
+		attribute.String("yalock.lock_name", name),
+		attribute.String("yalock.key", key),
+	))
+	return ctx, func(err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
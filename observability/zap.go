@@ -0,0 +1,19 @@
+package observability
+
+import "go.uber.org/zap"
+
+// ZapLogger adapts a *zap.SugaredLogger to Logger.
+type ZapLogger struct {
+	L *zap.SugaredLogger
+}
+
+func NewZapLogger(l *zap.SugaredLogger) ZapLogger {
+	return ZapLogger{L: l}
+}
+
+func (z ZapLogger) Debug(msg string, kv ...any) { z.L.Debugw(msg, kv...) }
+func (z ZapLogger) Info(msg string, kv ...any)  { z.L.Infow(msg, kv...) }
+func (z ZapLogger) Warn(msg string, kv ...any)  { z.L.Warnw(msg, kv...) }
+func (z ZapLogger) Error(msg string, kv ...any) { z.L.Errorw(msg, kv...) }
+
+var _ Logger = ZapLogger{}
@@ -27,4 +27,5 @@ var (
 	ErrorLockDoesNotExist      = errors.New("lock does not exist")
 	ErrorLockNotOwned          = errors.New("lock not owned")
 	ErrorLockUnknown           = errors.New("unknown error")
+	ErrorInvalidTimeout        = errors.New("invalid timeout")
 )
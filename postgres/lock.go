@@ -4,48 +4,219 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"hash/fnv"
+	"sync"
 	"time"
+
+	"github.com/bensooraj/yalock"
+	"github.com/bensooraj/yalock/observability"
 )
 
-func NewPostgreSQLLock(name string, db *sql.DB) *PostgreSQLLock {
-	return &PostgreSQLLock{name: name, db: db}
+var _ yalock.Locker = (*PostgreSQLLock)(nil)
+
+// Option configures optional behavior on a PostgreSQLLock.
+type Option func(*PostgreSQLLock)
+
+// WithLogger makes the lock emit structured events through logger instead
+// of discarding them.
+func WithLogger(logger observability.Logger) Option {
+	return func(l *PostgreSQLLock) { l.logger = logger }
+}
+
+// WithMetrics makes the lock emit acquire/release metrics through metrics
+// instead of discarding them.
+func WithMetrics(metrics observability.Metrics) Option {
+	return func(l *PostgreSQLLock) { l.metrics = metrics }
+}
+
+// NewPostgreSQLLock returns a PostgreSQLLock that checks out a dedicated
+// *sql.Conn from db on AcquireLock and pins it for the lifetime of the
+// lock, since pg_advisory_lock is scoped to the session (connection) that
+// took it, not to db as a whole.
+func NewPostgreSQLLock(name string, db *sql.DB, opts ...Option) *PostgreSQLLock {
+	l := &PostgreSQLLock{name: name, db: db}
+	l.init(opts)
+	return l
+}
+
+// NewPostgreSQLLockFromConn returns a PostgreSQLLock bound to an
+// already-checked-out conn, for callers who want to manage the connection's
+// lifetime themselves. The lock never closes conn.
+func NewPostgreSQLLockFromConn(name string, conn *sql.Conn, opts ...Option) *PostgreSQLLock {
+	l := &PostgreSQLLock{name: name, conn: conn}
+	l.init(opts)
+	return l
+}
+
+func (l *PostgreSQLLock) init(opts []Option) {
+	l.logger = observability.NoopLogger{}
+	l.metrics = observability.NoopMetrics{}
+	l.heldSince = make(map[string]time.Time)
+	for _, opt := range opts {
+		opt(l)
+	}
 }
 
 // Documentation: https://www.postgresql.org/docs/9.1/functions-admin.html
 type PostgreSQLLock struct {
 	name string
 	db   *sql.DB
+
+	logger  observability.Logger
+	metrics observability.Metrics
+
+	mu        sync.Mutex
+	conn      *sql.Conn
+	ownsConn  bool // true if this lock checked conn out of db itself
+	heldSince map[string]time.Time
 }
 
 func (l *PostgreSQLLock) Name() string {
 	return l.name
 }
 
-func (l *PostgreSQLLock) AcquireLock(ctx context.Context, key interface{}, timeout time.Duration) error {
-	// validate the arguments passments
-	keyI, ok := key.(int64)
-	if !ok {
-		return &LockError{
-			Err:         errors.New("key must be a 64-bit integer"),
-			Message:     "key must be a 64-bit integer",
-			Method:      "AcquireLock",
-			SessionName: l.name,
-			Driver:      "postgres",
-		}
+// lockKey maps an arbitrary string key onto the 64-bit integer space that
+// pg_advisory_lock and friends key on.
+func lockKey(key string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	return int64(h.Sum64())
+}
+
+// classAndObjID splits key the same way Postgres internally splits the
+// single-bigint form of an advisory lock id for display in pg_locks:
+// classid holds the upper 32 bits, objid the lower 32, both treated as
+// unsigned so the result matches what pg_locks reports.
+func classAndObjID(key int64) (classid, objid int64) {
+	u := uint64(key)
+	return int64(u >> 32), int64(uint32(u))
+}
+
+// querier is satisfied by both *sql.DB and *sql.Conn.
+type querier interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// querierFor returns the connection a read-only query (one that doesn't
+// need session affinity) can run against: the pinned conn if one is
+// checked out, otherwise the pool.
+func (l *PostgreSQLLock) querierFor() querier {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.conn != nil {
+		return l.conn
 	}
+	return l.db
+}
 
-	var (
-		result sql.NullBool
-		q string
-	)
-	// if timeout is negative, then wait indefinitely
+// AcquireLock is a thin wrapper around AcquireLockWithOptions. A negative
+// timeout retries pg_try_advisory_lock with a short constant backoff until
+// ctx is done, replacing the old "block inside pg_advisory_lock" behavior
+// that ignored ctx entirely; a non-negative timeout makes a single
+// pg_try_advisory_lock attempt, matching the previous behavior.
+func (l *PostgreSQLLock) AcquireLock(ctx context.Context, key string, timeout time.Duration) error {
 	if timeout < 0 {
-		q = "SELECT pg_advisory_lock(?)"
-	} else {	
-		q = "SELECT pg_try_advisory_lock(?)"
+		return l.AcquireLockWithOptions(ctx, key, yalock.AcquireOptions{
+			RetryPolicy: yalock.RetryPolicy{
+				Kind:   yalock.BackoffConstant,
+				Base:   100 * time.Millisecond,
+				Jitter: 50 * time.Millisecond,
+			},
+		})
 	}
+	return l.AcquireLockWithOptions(ctx, key, yalock.AcquireOptions{
+		Timeout:     timeout,
+		RetryPolicy: yalock.RetryPolicy{MaxAttempts: 1},
+	})
+}
+
+// AcquireLockWithOptions makes up to opts.RetryPolicy.MaxAttempts attempts
+// in total (or keeps going until ctx is done, if MaxAttempts is zero or
+// negative), interleaving pg_try_advisory_lock attempts with
+// opts.RetryPolicy's backoff. Only a denied attempt is retried; any other
+// error is returned at once. opts.Timeout is not used: each
+// pg_try_advisory_lock attempt returns immediately.
+func (l *PostgreSQLLock) AcquireLockWithOptions(ctx context.Context, key string, opts yalock.AcquireOptions) (err error) {
+	ctx, endSpan := observability.StartSpan(ctx, "Acquire", "postgres", l.name, key)
+	start := time.Now()
+	defer func() {
+		l.metrics.ObserveAcquireDuration("postgres", l.name, time.Since(start))
+		endSpan(err)
+	}()
+
+	unbounded := opts.RetryPolicy.MaxAttempts <= 0
+
+	for attempt := 0; unbounded || attempt < opts.RetryPolicy.MaxAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				err = ctx.Err()
+				return err
+			case <-time.After(opts.RetryPolicy.Delay(attempt - 1)):
+			}
+		}
+
+		l.metrics.IncAcquireAttempts("postgres", l.name)
+		err = l.tryAcquireOnce(ctx, key)
+		if err == nil {
+			l.logger.Info("lock acquired", "driver", "postgres", "name", l.name, "key", key)
+			l.mu.Lock()
+			l.heldSince[key] = time.Now()
+			l.metrics.SetLocksHeld("postgres", l.name, len(l.heldSince))
+			l.mu.Unlock()
+			return nil
+		}
+
+		l.metrics.IncAcquireFailures("postgres", l.name, postgresFailureReason(err))
+		if !errors.Is(err, ErrorLockAcquisitionFailed) {
+			l.logger.Error("lock acquisition failed", "driver", "postgres", "name", l.name, "key", key, "error", err)
+			return err
+		}
+		l.logger.Warn("lock denied, retrying", "driver", "postgres", "name", l.name, "key", key)
+	}
+	return err
+}
 
-	row := l.db.QueryRowContext(ctx, q, keyI)
+func postgresFailureReason(err error) observability.AcquireFailureReason {
+	switch {
+	case errors.Is(err, ErrorLockAcquisitionFailed):
+		return observability.ReasonDenied
+	default:
+		return observability.ReasonError
+	}
+}
+
+func (l *PostgreSQLLock) tryAcquireOnce(ctx context.Context, key string) (err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	checkedOut := false
+	if l.conn == nil {
+		conn, connErr := l.db.Conn(ctx)
+		if connErr != nil {
+			return &LockError{
+				Err:         connErr,
+				Message:     "failed to check out a dedicated connection",
+				Method:      "AcquireLock",
+				SessionName: l.name,
+				Driver:      "postgres",
+			}
+		}
+		l.conn = conn
+		l.ownsConn = true
+		checkedOut = true
+	}
+
+	defer func() {
+		if err != nil && checkedOut {
+			l.conn.Close()
+			l.conn = nil
+			l.ownsConn = false
+		}
+	}()
+
+	var result sql.NullBool
+	row := l.conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", lockKey(key))
 	if row.Err() != nil {
 		select {
 		case <-ctx.Done():
@@ -60,8 +231,7 @@ func (l *PostgreSQLLock) AcquireLock(ctx context.Context, key interface{}, timeo
 			return row.Err()
 		}
 	}
-	err := row.Scan(&result)
-	if err != nil {
+	if scanErr := row.Scan(&result); scanErr != nil {
 		select {
 		case <-ctx.Done():
 			return &LockError{
@@ -72,7 +242,7 @@ func (l *PostgreSQLLock) AcquireLock(ctx context.Context, key interface{}, timeo
 				Driver:      "postgres",
 			}
 		default:
-			return err
+			return scanErr
 		}
 	}
 
@@ -92,99 +262,152 @@ func (l *PostgreSQLLock) AcquireLock(ctx context.Context, key interface{}, timeo
 	return nil
 }
 
-func (l *PostgreSQLLock) ReleaseLock(ctx context.Context, key string) error {
-	var result sql.NullInt16
-	row := l.db.QueryRowContext(ctx, "SELECT RELEASE_LOCK(?)", key)
+func (l *PostgreSQLLock) ReleaseLock(ctx context.Context, key string) (err error) {
+	ctx, endSpan := observability.StartSpan(ctx, "Release", "postgres", l.name, key)
+	defer func() { endSpan(err) }()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		err = &LockError{
+			Err:         ErrorLockDoesNotExist,
+			Message:     "lock does not exist",
+			Method:      "ReleaseLock",
+			SessionName: l.name,
+			Driver:      "postgres",
+		}
+		return err
+	}
+
+	var result sql.NullBool
+	row := l.conn.QueryRowContext(ctx, "SELECT pg_advisory_unlock($1)", lockKey(key))
 	if row.Err() != nil {
 		return row.Err()
 	}
-	err := row.Scan(&result)
-	if err != nil {
+	if err = row.Scan(&result); err != nil {
 		return err
 	}
 	switch {
 	case !result.Valid: // NULL
 		// the named lock did not exist
-		return &LockError{
+		err = &LockError{
 			Err:         ErrorLockDoesNotExist,
 			Message:     "lock does not exist",
 			Method:      "ReleaseLock",
 			SessionName: l.name,
 			Driver:      "postgres",
 		}
-	case result.Int16 == 0:
-		// lock was not established by this thread (in which case the lock is not released)
-		return &LockError{
+		l.logger.Error("release failed: lock does not exist", "driver", "postgres", "name", l.name, "key", key)
+		return err
+	case !result.Bool:
+		// lock was not held by this session (in which case the lock is not released)
+		err = &LockError{
 			Err:         ErrorLockNotOwned,
 			Message:     "lock not owned",
 			Method:      "ReleaseLock",
 			SessionName: l.name,
 			Driver:      "postgres",
 		}
-	case result.Int16 == 1:
-		// log.Printf("[ReleaseLock::`%s`] lock on `%s` released", l.name, key)
+		l.logger.Error("release failed: lock not owned", "driver", "postgres", "name", l.name, "key", key)
+		return err
+	case result.Bool:
+		l.logger.Info("lock released", "driver", "postgres", "name", l.name, "key", key)
+	}
+
+	if since, ok := l.heldSince[key]; ok {
+		l.metrics.ObserveHeldDuration("postgres", l.name, time.Since(since))
+		delete(l.heldSince, key)
 	}
+	l.metrics.SetLocksHeld("postgres", l.name, len(l.heldSince))
+	l.metrics.IncRelease("postgres", l.name)
+
+	// The dedicated conn may still be backing other keys held by this
+	// lock; only give it up once none remain.
+	if l.ownsConn && len(l.heldSince) == 0 {
+		l.conn.Close()
+		l.conn = nil
+		l.ownsConn = false
+	}
+
 	return nil
 }
 
+// IsLockAcquired reports whether key is held by any session, by checking
+// pg_locks directly rather than trying to take the lock.
 func (l *PostgreSQLLock) IsLockAcquired(ctx context.Context, key string) (bool, error) {
-	var result sql.NullString
-	row := l.db.QueryRowContext(ctx, "SELECT IS_USED_LOCK(?)", key)
+	l.logger.Debug("checking pg_locks", "driver", "postgres", "name", l.name, "key", key)
+
+	classid, objid := classAndObjID(lockKey(key))
+
+	var result bool
+	row := l.querierFor().QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM pg_locks
+			WHERE locktype = 'advisory' AND classid = $1::oid AND objid = $2::oid AND objsubid = 1
+		)`, classid, objid)
 	if row.Err() != nil {
 		return false, row.Err()
 	}
-	err := row.Scan(&result)
-	if err != nil {
+	if err := row.Scan(&result); err != nil {
 		return false, err
 	}
-	switch {
-	case !result.Valid: // NULL
-		return false, nil
-	default:
-		return true, nil
-	}
+	return result, nil
 }
 
+// IsLockFree reports whether key is currently not held by any session.
 func (l *PostgreSQLLock) IsLockFree(ctx context.Context, key string) (bool, error) {
-	var result sql.NullInt16
-	row := l.db.QueryRowContext(ctx, "SELECT IS_FREE_LOCK(?)", key)
+	l.logger.Debug("checking pg_locks", "driver", "postgres", "name", l.name, "key", key)
+
+	classid, objid := classAndObjID(lockKey(key))
+
+	var result bool
+	row := l.querierFor().QueryRowContext(ctx, `
+		SELECT NOT EXISTS (
+			SELECT 1 FROM pg_locks
+			WHERE locktype = 'advisory' AND classid = $1::oid AND objid = $2::oid AND objsubid = 1
+		)`, classid, objid)
 	if row.Err() != nil {
 		return false, row.Err()
 	}
-	err := row.Scan(&result)
-	if err != nil {
+	if err := row.Scan(&result); err != nil {
 		return false, err
 	}
-
-	switch {
-	case !result.Valid: // NULL
-		// if an error occurs (such as an incorrect argument)
-		return false, &LockError{
-			Err:         ErrorLockUnknown,
-			Message:     "unknown error (possibly an incorrect argument)",
-			Method:      "IsLockFree",
-			SessionName: l.name,
-			Driver:      "postgres",
-		}
-	case result.Int16 == 0:
-		// Lock is in use
-		return false, nil
-	case result.Int16 == 1:
-		// Lock is free (no one is using the lock)
-		return true, nil
-	}
-	return false, nil
+	return result, nil
 }
 
+// ReleaseAllLocks releases every session-level advisory lock held on this
+// lock's dedicated connection and returns how many were released.
 func (l *PostgreSQLLock) ReleaseAllLocks(ctx context.Context) (int, error) {
-	var result sql.NullInt32
-	row := l.db.QueryRowContext(ctx, "SELECT RELEASE_ALL_LOCKS()")
+	l.logger.Debug("releasing all locks", "driver", "postgres", "name", l.name)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return 0, nil
+	}
+
+	var count int
+	row := l.conn.QueryRowContext(ctx, "SELECT count(*) FROM pg_locks WHERE locktype = 'advisory' AND pid = pg_backend_pid()")
 	if row.Err() != nil {
 		return 0, row.Err()
 	}
-	err := row.Scan(&result)
-	if err != nil {
+	if err := row.Scan(&count); err != nil {
 		return 0, err
 	}
-	return int(result.Int32), nil
+
+	if _, err := l.conn.ExecContext(ctx, "SELECT pg_advisory_unlock_all()"); err != nil {
+		return 0, err
+	}
+
+	if l.ownsConn {
+		l.conn.Close()
+		l.conn = nil
+		l.ownsConn = false
+	}
+	l.heldSince = make(map[string]time.Time)
+	l.metrics.SetLocksHeld("postgres", l.name, 0)
+
+	return count, nil
 }
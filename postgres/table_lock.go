@@ -0,0 +1,248 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/bensooraj/yalock"
+)
+
+var _ yalock.Locker = (*TableLock)(nil)
+
+// EnsureSchema creates the yalock_locks table (and its index) TableLock
+// relies on, if they don't already exist. Call it once during application
+// startup or migration.
+func EnsureSchema(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS yalock_locks (
+			name       TEXT PRIMARY KEY,
+			owner      TEXT NOT NULL,
+			expires_at TIMESTAMPTZ NOT NULL
+		)`)
+	if err != nil {
+		return err
+	}
+	_, err = db.ExecContext(ctx, `
+		CREATE INDEX IF NOT EXISTS yalock_locks_expires_at_idx ON yalock_locks (expires_at)`)
+	return err
+}
+
+// TableLock implements yalock.Locker on top of a plain yalock_locks table
+// instead of Postgres advisory locks. Unlike PostgreSQLLock, it accepts
+// string keys directly, survives across connections (ownership is a row,
+// not session state), and "who holds what" is a normal SELECT against
+// yalock_locks.
+//
+// Ownership is a random fencing token generated per acquisition, not
+// name: name is only a display label (see Locker.Name), and two TableLock
+// instances are commonly constructed with the same name to contend for
+// the same lock, so using it as the owner would let any of them release
+// or extend a lock actually held by another.
+//
+// Because ownership isn't tied to a session, AcquireLock's timeout is used
+// as the lease TTL rather than a wait-for-availability timeout: a claim
+// either succeeds immediately (the row is free or expired) or fails
+// immediately (it's held by someone else and still live). A negative
+// timeout ("wait indefinitely" for the session-scoped drivers) has no
+// sensible lease-duration meaning here, so it is rejected rather than
+// silently producing an already-expired row; use yalock.WithLock or your
+// own retry loop if you need to wait.
+type TableLock struct {
+	name string
+	db   *sql.DB
+
+	mu     sync.Mutex
+	tokens map[string]string // key -> fencing token of the lock this instance holds
+}
+
+func NewTableLock(name string, db *sql.DB) *TableLock {
+	return &TableLock{name: name, db: db, tokens: make(map[string]string)}
+}
+
+func (l *TableLock) Name() string {
+	return l.name
+}
+
+func (l *TableLock) AcquireLock(ctx context.Context, key string, timeout time.Duration) error {
+	if timeout < 0 {
+		return &LockError{
+			Err:         ErrorInvalidTimeout,
+			Message:     "negative timeout is not supported: TableLock claims are immediate, not wait-for-availability; use yalock.WithLock or your own retry loop instead",
+			Method:      "AcquireLock",
+			SessionName: l.name,
+			Driver:      "postgres",
+		}
+	}
+
+	token := uuid.NewString()
+
+	res, err := l.db.ExecContext(ctx, `
+		INSERT INTO yalock_locks (name, owner, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (name) DO UPDATE
+			SET owner = EXCLUDED.owner, expires_at = EXCLUDED.expires_at
+			WHERE yalock_locks.expires_at < now()`,
+		key, token, time.Now().Add(timeout))
+	if err != nil {
+		return &LockError{
+			Err:         err,
+			Message:     "failed to claim lock row",
+			Method:      "AcquireLock",
+			SessionName: l.name,
+			Driver:      "postgres",
+		}
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return &LockError{
+			Err:         ErrorLockAcquisitionFailed,
+			Message:     "failed to acquire lock",
+			Method:      "AcquireLock",
+			SessionName: l.name,
+			Driver:      "postgres",
+		}
+	}
+
+	l.mu.Lock()
+	l.tokens[key] = token
+	l.mu.Unlock()
+	return nil
+}
+
+func (l *TableLock) ReleaseLock(ctx context.Context, key string) error {
+	l.mu.Lock()
+	token, ok := l.tokens[key]
+	l.mu.Unlock()
+	if !ok {
+		return &LockError{
+			Err:         ErrorLockNotOwned,
+			Message:     "lock not owned",
+			Method:      "ReleaseLock",
+			SessionName: l.name,
+			Driver:      "postgres",
+		}
+	}
+
+	res, err := l.db.ExecContext(ctx, "DELETE FROM yalock_locks WHERE name = $1 AND owner = $2", key, token)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return &LockError{
+			Err:         ErrorLockNotOwned,
+			Message:     "lock not owned",
+			Method:      "ReleaseLock",
+			SessionName: l.name,
+			Driver:      "postgres",
+		}
+	}
+
+	l.mu.Lock()
+	delete(l.tokens, key)
+	l.mu.Unlock()
+	return nil
+}
+
+// Extend pushes out key's expiry by ttl, provided it's still owned by
+// this lock. Callers that need a lease kept alive beyond the original
+// timeout should call Extend before it expires.
+func (l *TableLock) Extend(ctx context.Context, key string, ttl time.Duration) error {
+	l.mu.Lock()
+	token, ok := l.tokens[key]
+	l.mu.Unlock()
+	if !ok {
+		return &LockError{
+			Err:         ErrorLockNotOwned,
+			Message:     "lock not owned",
+			Method:      "Extend",
+			SessionName: l.name,
+			Driver:      "postgres",
+		}
+	}
+
+	res, err := l.db.ExecContext(ctx, "UPDATE yalock_locks SET expires_at = $3 WHERE name = $1 AND owner = $2",
+		key, token, time.Now().Add(ttl))
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return &LockError{
+			Err:         ErrorLockNotOwned,
+			Message:     "lock not owned",
+			Method:      "Extend",
+			SessionName: l.name,
+			Driver:      "postgres",
+		}
+	}
+	return nil
+}
+
+func (l *TableLock) IsLockAcquired(ctx context.Context, key string) (bool, error) {
+	var acquired bool
+	row := l.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM yalock_locks WHERE name = $1 AND expires_at >= now())", key)
+	if row.Err() != nil {
+		return false, row.Err()
+	}
+	if err := row.Scan(&acquired); err != nil {
+		return false, err
+	}
+	return acquired, nil
+}
+
+func (l *TableLock) IsLockFree(ctx context.Context, key string) (bool, error) {
+	acquired, err := l.IsLockAcquired(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	return !acquired, nil
+}
+
+// ReleaseAllLocks releases every key currently held by this instance (i.e.
+// every token handed out by a successful AcquireLock on it) and returns
+// how many were released.
+func (l *TableLock) ReleaseAllLocks(ctx context.Context) (int, error) {
+	l.mu.Lock()
+	tokens := make(map[string]string, len(l.tokens))
+	for key, token := range l.tokens {
+		tokens[key] = token
+	}
+	l.mu.Unlock()
+
+	var count int
+	for key, token := range tokens {
+		res, err := l.db.ExecContext(ctx, "DELETE FROM yalock_locks WHERE name = $1 AND owner = $2", key, token)
+		if err != nil {
+			return count, err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return count, err
+		}
+		if n == 0 {
+			continue
+		}
+		count += int(n)
+		l.mu.Lock()
+		delete(l.tokens, key)
+		l.mu.Unlock()
+	}
+	return count, nil
+}
@@ -0,0 +1,28 @@
+package redis
+
+import (
+	"errors"
+	"fmt"
+)
+
+type LockError struct {
+	Err         error
+	Message     string
+	Method      string
+	SessionName string
+	Driver      string
+}
+
+func (e *LockError) Error() string {
+	return fmt.Sprintf("%s::%s::%s::%s::%s", e.Driver, e.Method, e.SessionName, e.Message, e.Err.Error())
+}
+
+func (e *LockError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	ErrorLockAcquisitionFailed = errors.New("failed to acquire lock on a majority of nodes")
+	ErrorLockReleaseFailed     = errors.New("failed to release lock on a majority of nodes")
+	ErrorLockNotOwned          = errors.New("lock not owned")
+)
@@ -0,0 +1,329 @@
+package redis
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bensooraj/yalock"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+var _ yalock.Locker = (*RedisLock)(nil)
+
+const (
+	// retryDelay is the upper bound of the jittered delay between
+	// acquisition retries.
+	retryDelay = 200 * time.Millisecond
+	// driftFactor and minDrift follow the Redlock specification's
+	// formula for clock drift: ttl*driftFactor + minDrift.
+	driftFactor = 0.01
+	minDrift    = 2 * time.Millisecond
+	// defaultTTL is the default Redis key TTL (lease duration), used
+	// unless overridden with WithTTL. Unlike mysql/postgres, Redis locks
+	// aren't tied to a live session, so they need an explicit lease
+	// independent of AcquireLock's timeout, which only bounds how long
+	// AcquireLock keeps retrying.
+	defaultTTL = 30 * time.Second
+)
+
+// releaseScript deletes key only if it still holds the value this locker
+// set, so a client can never release a lock it no longer owns.
+const releaseScript = `
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+else
+	return 0
+end
+`
+
+// extendScript re-asserts this locker's ownership of key by resetting its
+// TTL, but only if the value still matches.
+const extendScript = `
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+else
+	return 0
+end
+`
+
+// Option configures optional behavior on a RedisLock.
+type Option func(*RedisLock)
+
+// WithTTL overrides the Redis key TTL (lease duration) locks acquired by
+// this instance carry. The default is defaultTTL.
+func WithTTL(ttl time.Duration) Option {
+	return func(l *RedisLock) { l.ttl = ttl }
+}
+
+// Documentation: https://redis.io/docs/manual/patterns/distributed-locks/
+//
+// RedisLock implements yalock.Locker via the Redlock algorithm across N
+// independent Redis nodes: a lock is considered acquired only once a
+// strict majority (N/2+1) of nodes accept it within the lock's TTL.
+type RedisLock struct {
+	name   string
+	nodes  []*goredis.Client
+	quorum int
+	ttl    time.Duration // lease duration set on the underlying Redis keys
+
+	mu    sync.Mutex
+	key   string
+	token string
+}
+
+func NewRedisLock(name string, nodes []*goredis.Client, opts ...Option) *RedisLock {
+	l := &RedisLock{
+		name:   name,
+		nodes:  nodes,
+		quorum: len(nodes)/2 + 1,
+		ttl:    defaultTTL,
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+func (l *RedisLock) Name() string {
+	return l.name
+}
+
+// AcquireLock retries SET NX PX on a majority of nodes until it succeeds,
+// timeout elapses, or ctx is done, same wait-for-availability contract as
+// the mysql and postgres drivers. A negative timeout means retry
+// indefinitely until ctx is done.
+//
+// timeout is purely the retry budget: it does not affect the lease given
+// to the Redis keys themselves, which always use l.ttl (defaultTTL unless
+// overridden with WithTTL), since unlike mysql/postgres a Redis lock isn't
+// tied to a live session and needs an explicit lease regardless of how
+// long the caller is willing to wait to acquire it.
+func (l *RedisLock) AcquireLock(ctx context.Context, key string, timeout time.Duration) error {
+	unbounded := timeout < 0
+	deadline := time.Now().Add(timeout)
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(jitter(retryDelay)):
+			}
+		}
+
+		token, err := randomToken()
+		if err != nil {
+			return err
+		}
+
+		start := time.Now()
+		acquired := l.setOnNodes(ctx, key, token, l.ttl)
+		elapsed := time.Since(start)
+
+		drift := time.Duration(float64(l.ttl)*driftFactor) + minDrift
+		validity := l.ttl - elapsed - drift
+
+		if acquired >= l.quorum && validity > 0 {
+			l.mu.Lock()
+			l.key, l.token = key, token
+			l.mu.Unlock()
+			return nil
+		}
+
+		// Partial acquisition: release what we got before retrying, so a
+		// future attempt (by us or another client) isn't blocked by it.
+		l.releaseOnNodes(context.Background(), key, token)
+
+		if !unbounded && time.Now().After(deadline) {
+			break
+		}
+	}
+
+	return &LockError{
+		Err:         ErrorLockAcquisitionFailed,
+		Message:     "failed to acquire lock on a majority of nodes",
+		Method:      "AcquireLock",
+		SessionName: l.name,
+		Driver:      "redis",
+	}
+}
+
+func (l *RedisLock) ReleaseLock(ctx context.Context, key string) error {
+	l.mu.Lock()
+	heldKey, token := l.key, l.token
+	l.mu.Unlock()
+
+	if token == "" || heldKey != key {
+		return &LockError{
+			Err:         ErrorLockNotOwned,
+			Message:     "lock not owned",
+			Method:      "ReleaseLock",
+			SessionName: l.name,
+			Driver:      "redis",
+		}
+	}
+
+	released := l.releaseOnNodes(ctx, key, token)
+
+	l.mu.Lock()
+	l.key, l.token = "", ""
+	l.mu.Unlock()
+
+	if released < l.quorum {
+		return &LockError{
+			Err:         ErrorLockReleaseFailed,
+			Message:     "failed to release lock on a majority of nodes",
+			Method:      "ReleaseLock",
+			SessionName: l.name,
+			Driver:      "redis",
+		}
+	}
+	return nil
+}
+
+// Refresh re-asserts ownership of the currently held lock on a majority of
+// nodes, extending its TTL. Callers needing to keep a long-running lock
+// alive should call Refresh well before the original TTL elapses.
+func (l *RedisLock) Refresh(ctx context.Context) error {
+	l.mu.Lock()
+	key, token, ttl := l.key, l.token, l.ttl
+	l.mu.Unlock()
+
+	if token == "" {
+		return &LockError{
+			Err:         ErrorLockNotOwned,
+			Message:     "lock not owned",
+			Method:      "Refresh",
+			SessionName: l.name,
+			Driver:      "redis",
+		}
+	}
+
+	var wg sync.WaitGroup
+	var count int32
+	for _, node := range l.nodes {
+		wg.Add(1)
+		go func(n *goredis.Client) {
+			defer wg.Done()
+			res, err := n.Eval(ctx, extendScript, []string{key}, token, ttl.Milliseconds()).Result()
+			if err == nil {
+				if v, ok := res.(int64); ok && v == 1 {
+					atomic.AddInt32(&count, 1)
+				}
+			}
+		}(node)
+	}
+	wg.Wait()
+
+	if int(count) < l.quorum {
+		return &LockError{
+			Err:         ErrorLockAcquisitionFailed,
+			Message:     "failed to extend lock on a majority of nodes",
+			Method:      "Refresh",
+			SessionName: l.name,
+			Driver:      "redis",
+		}
+	}
+	return nil
+}
+
+func (l *RedisLock) IsLockAcquired(ctx context.Context, key string) (bool, error) {
+	var wg sync.WaitGroup
+	var count int32
+	for _, node := range l.nodes {
+		wg.Add(1)
+		go func(n *goredis.Client) {
+			defer wg.Done()
+			exists, err := n.Exists(ctx, key).Result()
+			if err == nil && exists == 1 {
+				atomic.AddInt32(&count, 1)
+			}
+		}(node)
+	}
+	wg.Wait()
+	return int(count) >= l.quorum, nil
+}
+
+func (l *RedisLock) IsLockFree(ctx context.Context, key string) (bool, error) {
+	acquired, err := l.IsLockAcquired(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	return !acquired, nil
+}
+
+// ReleaseAllLocks releases the lock currently held by this locker, if any.
+// Unlike the session-scoped MySQL/Postgres drivers, a RedisLock only ever
+// tracks the single key it last acquired.
+func (l *RedisLock) ReleaseAllLocks(ctx context.Context) (int, error) {
+	l.mu.Lock()
+	key := l.key
+	l.mu.Unlock()
+
+	if key == "" {
+		return 0, nil
+	}
+	if err := l.ReleaseLock(ctx, key); err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
+
+func (l *RedisLock) setOnNodes(ctx context.Context, key, token string, ttl time.Duration) int {
+	var wg sync.WaitGroup
+	var count int32
+	for _, node := range l.nodes {
+		wg.Add(1)
+		go func(n *goredis.Client) {
+			defer wg.Done()
+			ok, err := n.SetNX(ctx, key, token, ttl).Result()
+			if err == nil && ok {
+				atomic.AddInt32(&count, 1)
+			}
+		}(node)
+	}
+	wg.Wait()
+	return int(count)
+}
+
+func (l *RedisLock) releaseOnNodes(ctx context.Context, key, token string) int {
+	var wg sync.WaitGroup
+	var count int32
+	for _, node := range l.nodes {
+		wg.Add(1)
+		go func(n *goredis.Client) {
+			defer wg.Done()
+			res, err := n.Eval(ctx, releaseScript, []string{key}, token).Result()
+			if err == nil {
+				if v, ok := res.(int64); ok && v == 1 {
+					atomic.AddInt32(&count, 1)
+				}
+			}
+		}(node)
+	}
+	wg.Wait()
+	return int(count)
+}
+
+func randomToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// jitter returns a random duration in [0, max), used to spread out retries
+// across clients contending for the same lock.
+func jitter(max time.Duration) time.Duration {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(max)))
+	if err != nil {
+		return max
+	}
+	return time.Duration(n.Int64())
+}
@@ -0,0 +1,85 @@
+package yalock
+
+import (
+	"math/rand"
+	"time"
+)
+
+// BackoffKind selects the growth function RetryPolicy.Delay uses between
+// acquisition attempts.
+type BackoffKind int
+
+const (
+	// BackoffConstant waits Base between every attempt.
+	BackoffConstant BackoffKind = iota
+	// BackoffExponential waits Base*2^attempt.
+	BackoffExponential
+	// BackoffFibonacci waits Base*fib(attempt+1).
+	BackoffFibonacci
+)
+
+// RetryPolicy controls how AcquireLockWithOptions spaces out repeated
+// acquisition attempts after the first one fails or times out.
+type RetryPolicy struct {
+	// Kind selects how the delay grows with each attempt. The zero value
+	// is BackoffConstant.
+	Kind BackoffKind
+	// Base is the unit delay: the constant delay for BackoffConstant, or
+	// the starting delay for BackoffExponential/BackoffFibonacci.
+	Base time.Duration
+	// MaxDelay caps the computed delay before jitter is added. Zero
+	// means uncapped.
+	MaxDelay time.Duration
+	// Jitter adds a random delay in [0, Jitter) on top of the computed
+	// delay, to avoid contending clients retrying in lockstep.
+	Jitter time.Duration
+	// MaxAttempts bounds how many acquisition attempts are made in
+	// total. Zero or negative means retry until the caller's context is
+	// done.
+	MaxAttempts int
+}
+
+// Delay returns the backoff delay to wait before making attempt (0-indexed:
+// attempt 0 is the delay before the second overall try).
+func (p RetryPolicy) Delay(attempt int) time.Duration {
+	var d time.Duration
+	switch p.Kind {
+	case BackoffExponential:
+		d = p.Base << attempt
+	case BackoffFibonacci:
+		d = p.Base * time.Duration(fibonacci(attempt+1))
+	default: // BackoffConstant
+		d = p.Base
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if p.Jitter > 0 {
+		d += time.Duration(rand.Int63n(int64(p.Jitter)))
+	}
+	return d
+}
+
+func fibonacci(n int) int64 {
+	var a, b int64 = 0, 1
+	for i := 0; i < n; i++ {
+		a, b = b, a+b
+	}
+	return a
+}
+
+// AcquireOptions configures a single AcquireLockWithOptions call.
+type AcquireOptions struct {
+	// Timeout is the per-attempt timeout passed to the driver's
+	// underlying acquisition primitive (e.g. MySQL's GET_LOCK timeout).
+	// A negative Timeout means wait indefinitely within a single
+	// attempt, same as AcquireLock. Drivers whose primitive does not
+	// block, such as postgres.PostgreSQLLock's pg_try_advisory_lock,
+	// ignore it.
+	Timeout time.Duration
+	// RetryPolicy governs whether and how additional attempts are made
+	// after the first one times out or is denied. Its zero value
+	// retries with no delay until the caller's context is done; pass an
+	// explicit MaxAttempts of 1 for a single attempt.
+	RetryPolicy RetryPolicy
+}
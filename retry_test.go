@@ -0,0 +1,46 @@
+package yalock_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bensooraj/yalock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy_Delay_Constant(t *testing.T) {
+	p := yalock.RetryPolicy{Kind: yalock.BackoffConstant, Base: 50 * time.Millisecond}
+	assert.Equal(t, 50*time.Millisecond, p.Delay(0))
+	assert.Equal(t, 50*time.Millisecond, p.Delay(3))
+}
+
+func TestRetryPolicy_Delay_Exponential(t *testing.T) {
+	p := yalock.RetryPolicy{Kind: yalock.BackoffExponential, Base: 10 * time.Millisecond}
+	assert.Equal(t, 10*time.Millisecond, p.Delay(0))
+	assert.Equal(t, 20*time.Millisecond, p.Delay(1))
+	assert.Equal(t, 40*time.Millisecond, p.Delay(2))
+}
+
+func TestRetryPolicy_Delay_Fibonacci(t *testing.T) {
+	p := yalock.RetryPolicy{Kind: yalock.BackoffFibonacci, Base: 10 * time.Millisecond}
+	assert.Equal(t, 10*time.Millisecond, p.Delay(0)) // fib(1) = 1
+	assert.Equal(t, 10*time.Millisecond, p.Delay(1)) // fib(2) = 1
+	assert.Equal(t, 20*time.Millisecond, p.Delay(2)) // fib(3) = 2
+	assert.Equal(t, 30*time.Millisecond, p.Delay(3)) // fib(4) = 3
+}
+
+func TestRetryPolicy_Delay_MaxDelay(t *testing.T) {
+	p := yalock.RetryPolicy{Kind: yalock.BackoffExponential, Base: 10 * time.Millisecond, MaxDelay: 25 * time.Millisecond}
+	assert.Equal(t, 10*time.Millisecond, p.Delay(0))
+	assert.Equal(t, 20*time.Millisecond, p.Delay(1))
+	assert.Equal(t, 25*time.Millisecond, p.Delay(2)) // would be 40ms uncapped
+}
+
+func TestRetryPolicy_Delay_Jitter(t *testing.T) {
+	p := yalock.RetryPolicy{Kind: yalock.BackoffConstant, Base: 10 * time.Millisecond, Jitter: 5 * time.Millisecond}
+	for i := 0; i < 20; i++ {
+		d := p.Delay(0)
+		assert.GreaterOrEqual(t, d, 10*time.Millisecond)
+		assert.Less(t, d, 15*time.Millisecond)
+	}
+}
@@ -0,0 +1,103 @@
+package yalock
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ReleaseError wraps an error returned by ReleaseLock inside WithLock,
+// together with the callback's error or recovered panic if there was one,
+// so callers can recover both via errors.As/errors.Is without losing
+// either one.
+type ReleaseError struct {
+	// Err is the error or recovered panic value from the callback, if any.
+	Err error
+	// ReleaseErr is the error ReleaseLock itself returned.
+	ReleaseErr error
+}
+
+func (e *ReleaseError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("yalock: release failed: %s", e.ReleaseErr)
+	}
+	return fmt.Sprintf("yalock: release failed: %s (after: %s)", e.ReleaseErr, e.Err)
+}
+
+func (e *ReleaseError) Unwrap() []error {
+	return []error{e.Err, e.ReleaseErr}
+}
+
+// WithLock acquires key on locker, invokes fn, and releases the lock
+// afterward regardless of how fn returns, including via panic. A panic in
+// fn is recovered, the lock is released, and the panic is then re-raised
+// so the caller sees it as if WithLock weren't there. If ReleaseLock
+// itself fails, the error (or re-raised panic) is combined with it into a
+// *ReleaseError instead of being silently dropped.
+func WithLock(ctx context.Context, locker Locker, key string, timeout time.Duration, fn func(ctx context.Context) error) (err error) {
+	if err := locker.AcquireLock(ctx, key, timeout); err != nil {
+		return err
+	}
+
+	var panicked any
+	func() {
+		defer func() {
+			panicked = recover()
+		}()
+		err = fn(ctx)
+	}()
+
+	if relErr := locker.ReleaseLock(ctx, key); relErr != nil {
+		if panicked != nil {
+			panic(&ReleaseError{Err: asError(panicked), ReleaseErr: relErr})
+		}
+		err = &ReleaseError{Err: err, ReleaseErr: relErr}
+		return err
+	}
+
+	if panicked != nil {
+		panic(panicked)
+	}
+	return err
+}
+
+func asError(v any) error {
+	if v == nil {
+		return nil
+	}
+	if err, ok := v.(error); ok {
+		return err
+	}
+	return fmt.Errorf("%v", v)
+}
+
+// StdLocker adapts locker to sync.Locker for key, so it can be used with
+// code that expects the standard library's locking interface. Since
+// sync.Locker's Lock and Unlock have no error return, AcquireLock and
+// ReleaseLock failures are reported by panicking; callers that need to
+// handle errors should use locker directly instead.
+func StdLocker(ctx context.Context, locker Locker, key string, timeout time.Duration) sync.Locker {
+	return &stdLocker{ctx: ctx, locker: locker, key: key, timeout: timeout}
+}
+
+type stdLocker struct {
+	ctx     context.Context
+	locker  Locker
+	key     string
+	timeout time.Duration
+}
+
+func (s *stdLocker) Lock() {
+	if err := s.locker.AcquireLock(s.ctx, s.key, s.timeout); err != nil {
+		panic(err)
+	}
+}
+
+func (s *stdLocker) Unlock() {
+	if err := s.locker.ReleaseLock(s.ctx, s.key); err != nil {
+		panic(err)
+	}
+}
+
+var _ sync.Locker = (*stdLocker)(nil)
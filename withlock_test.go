@@ -0,0 +1,131 @@
+package yalock_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bensooraj/yalock"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeLocker struct {
+	acquireErr error
+	releaseErr error
+	acquired   bool
+	released   bool
+}
+
+func (f *fakeLocker) Name() string { return "fake" }
+
+func (f *fakeLocker) AcquireLock(ctx context.Context, key string, timeout time.Duration) error {
+	if f.acquireErr != nil {
+		return f.acquireErr
+	}
+	f.acquired = true
+	return nil
+}
+
+func (f *fakeLocker) ReleaseLock(ctx context.Context, key string) error {
+	f.released = true
+	return f.releaseErr
+}
+
+func (f *fakeLocker) IsLockAcquired(ctx context.Context, key string) (bool, error) {
+	return f.acquired, nil
+}
+
+func (f *fakeLocker) IsLockFree(ctx context.Context, key string) (bool, error) {
+	return !f.acquired, nil
+}
+
+func (f *fakeLocker) ReleaseAllLocks(ctx context.Context) (int, error) {
+	return 0, nil
+}
+
+func TestWithLock_Success(t *testing.T) {
+	l := &fakeLocker{}
+	var called bool
+
+	err := yalock.WithLock(context.Background(), l, "key", time.Second, func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, called)
+	assert.True(t, l.released)
+}
+
+func TestWithLock_AcquireFails(t *testing.T) {
+	wantErr := errors.New("boom")
+	l := &fakeLocker{acquireErr: wantErr}
+	var called bool
+
+	err := yalock.WithLock(context.Background(), l, "key", time.Second, func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+
+	assert.ErrorIs(t, err, wantErr)
+	assert.False(t, called)
+	assert.False(t, l.released)
+}
+
+func TestWithLock_CallbackErrorStillReleases(t *testing.T) {
+	wantErr := errors.New("callback failed")
+	l := &fakeLocker{}
+
+	err := yalock.WithLock(context.Background(), l, "key", time.Second, func(ctx context.Context) error {
+		return wantErr
+	})
+
+	assert.ErrorIs(t, err, wantErr)
+	assert.True(t, l.released)
+}
+
+func TestWithLock_PanicReleasesAndRePanics(t *testing.T) {
+	l := &fakeLocker{}
+
+	assert.PanicsWithValue(t, "boom", func() {
+		_ = yalock.WithLock(context.Background(), l, "key", time.Second, func(ctx context.Context) error {
+			panic("boom")
+		})
+	})
+	assert.True(t, l.released)
+}
+
+func TestWithLock_ReleaseFailureCombinesErrors(t *testing.T) {
+	relErr := errors.New("release failed")
+	cbErr := errors.New("callback failed")
+	l := &fakeLocker{releaseErr: relErr}
+
+	err := yalock.WithLock(context.Background(), l, "key", time.Second, func(ctx context.Context) error {
+		return cbErr
+	})
+
+	var releaseErr *yalock.ReleaseError
+	assert.ErrorAs(t, err, &releaseErr)
+	assert.ErrorIs(t, err, relErr)
+	assert.ErrorIs(t, err, cbErr)
+}
+
+func TestStdLocker(t *testing.T) {
+	l := &fakeLocker{}
+	std := yalock.StdLocker(context.Background(), l, "key", time.Second)
+
+	std.Lock()
+	assert.True(t, l.acquired)
+
+	std.Unlock()
+	assert.True(t, l.released)
+}
+
+func TestStdLocker_PanicsOnAcquireFailure(t *testing.T) {
+	wantErr := errors.New("boom")
+	l := &fakeLocker{acquireErr: wantErr}
+	std := yalock.StdLocker(context.Background(), l, "key", time.Second)
+
+	assert.PanicsWithValue(t, wantErr, std.Lock)
+}